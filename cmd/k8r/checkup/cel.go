@@ -0,0 +1,138 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the declarative, CEL-based problem
+// loader for `--problems`, letting ops teams add cluster-specific
+// checks without recompiling k8r.
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// ProblemConfig is a single declaratively-defined problem loaded from a
+// --problems config file, e.g.:
+//
+//   - id: TooManyRestarts
+//     shortDescription: Container has restarted more than 5 times
+//     severity: warning
+//     resource: pod
+//     expression: object.status.containerStatuses.exists(c, c.restartCount > 5)
+type ProblemConfig struct {
+	ID               string `json:"id"`
+	ShortDescription string `json:"shortDescription"`
+	Severity         string `json:"severity"`
+	HelpURL          string `json:"helpURL"`
+	Resource         string `json:"resource"`
+	Expression       string `json:"expression"`
+}
+
+// LoadProblems reads a --problems config file and compiles each entry's
+// CEL expression once, returning a Problem per entry ready to be passed
+// to RegisterProblem. Only resource: pod is currently supported; any
+// other value is rejected with an error, since no reporter evaluates
+// the CEL registry against deployments or nodes yet.
+func LoadProblems(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read problems config")
+	}
+
+	var configs []ProblemConfig
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, errors.Wrap(err, "failed to parse problems config")
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CEL environment")
+	}
+
+	problems := make([]Problem, 0, len(configs))
+	for _, c := range configs {
+		p, err := c.compile(env)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile problem %q", c.ID)
+		}
+		problems = append(problems, p)
+	}
+
+	return problems, nil
+}
+
+// compile compiles c's CEL expression and returns a Problem whose
+// Detector evaluates it against the pod being checked. It rejects any
+// c.Resource other than "pod" (the empty string defaults to "pod"),
+// since that's the only kind currently wired up to evaluate the CEL
+// registry.
+func (c ProblemConfig) compile(env *cel.Env) (Problem, error) {
+	switch c.Resource {
+	case "", "pod":
+	default:
+		return Problem{}, errors.Errorf(
+			"unsupported resource %q: --problems currently only supports \"pod\"", c.Resource)
+	}
+
+	ast, iss := env.Compile(c.Expression)
+	if iss != nil && iss.Err() != nil {
+		return Problem{}, iss.Err()
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return Problem{}, err
+	}
+
+	warning := c.Severity == "warning"
+
+	return Problem{
+		ID:               c.ID,
+		ShortDescription: c.ShortDescription,
+		HelpURL:          c.HelpURL,
+		Detector: func(_ context.Context, obj runtime.Object) (string, bool, bool) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				return "", false, false
+			}
+
+			celObj, err := toCELObject(pod)
+			if err != nil {
+				return err.Error(), warning, true
+			}
+
+			out, _, err := prg.Eval(map[string]interface{}{"object": celObj})
+			if err != nil {
+				return err.Error(), warning, true
+			}
+
+			occurring, ok := out.Value().(bool)
+			return c.ShortDescription, warning, ok && occurring
+		},
+	}, nil
+}
+
+// toCELObject converts a Kubernetes object to the plain
+// map[string]interface{} shape CEL expressions are evaluated against,
+// matching the object's JSON field names (e.g.
+// object.status.containerStatuses).
+func toCELObject(obj interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
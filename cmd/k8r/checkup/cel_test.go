@@ -0,0 +1,68 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: Tests for the declarative, CEL-based problem loader.
+
+package debug
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newCELEnv(t *testing.T) *cel.Env {
+	t.Helper()
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		t.Fatalf("cel.NewEnv() error = %v", err)
+	}
+
+	return env
+}
+
+func TestProblemConfigCompileAndEval(t *testing.T) {
+	env := newCELEnv(t)
+
+	cfg := ProblemConfig{
+		ID:               "TooManyRestarts",
+		ShortDescription: "too many restarts",
+		Severity:         "warning",
+		Expression:       "object.status.containerStatuses.exists(c, c.restartCount > 5)",
+	}
+
+	problem, err := cfg.compile(env)
+	if err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+
+	healthy := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 1}}}}
+	if _, _, occurring := problem.Detector(context.Background(), healthy); occurring {
+		t.Fatalf("Detector() occurring = true for a healthy pod, want false")
+	}
+
+	flaky := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 6}}}}
+	_, warning, occurring := problem.Detector(context.Background(), flaky)
+	if !occurring {
+		t.Fatalf("Detector() occurring = false for a flaky pod, want true")
+	}
+	if !warning {
+		t.Fatalf(`Detector() warning = false for a "severity: warning" config, want true`)
+	}
+}
+
+func TestProblemConfigCompileRejectsUnsupportedResource(t *testing.T) {
+	env := newCELEnv(t)
+
+	cfg := ProblemConfig{
+		ID:         "NodeThing",
+		Resource:   "node",
+		Expression: "true",
+	}
+
+	if _, err := cfg.compile(env); err == nil {
+		t.Fatal("compile() error = nil, want an error for an unsupported resource")
+	}
+}
@@ -16,8 +16,10 @@ package debug
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/getoutreach/devenv/pkg/kube"
@@ -25,8 +27,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // enabledProblems is a list of problems checkers that are enabled
@@ -37,6 +38,15 @@ var enabledProblems = []Problem{
 	ProblemPodOOMKilled,
 }
 
+// RegisterProblem adds p to the set of known problems, alongside the
+// built-in ones. A p with a Detector is checked for on every pod; one
+// without (e.g. the deployment, statefulset and node problems) is only
+// registered so the report can look it up. This is how --problems
+// registers the declarative, CEL-based problems it loads.
+func RegisterProblem(p Problem) {
+	enabledProblems = append(enabledProblems, p)
+}
+
 // contains string helpers
 var (
 	// bold returns a string in bold
@@ -48,6 +58,40 @@ var (
 // command
 type Options struct {
 	log logrus.FieldLogger
+
+	// Fix, when set, attempts to remediate detected problems instead of
+	// just reporting them.
+	Fix bool
+
+	// GracePeriod is the termination grace period set on the pods a
+	// remediation evicts or deletes.
+	GracePeriod time.Duration
+
+	// Dump, when non-empty, writes a diagnostics bundle to this
+	// directory: report.json, previous container logs for crash-looping
+	// or OOM-killed pods, and an in-pod exec probe for not-ready pods.
+	Dump string
+
+	// DumpExecCmd is the command run in-pod to probe not-ready pods
+	// when Dump is set.
+	DumpExecCmd string
+
+	// Output is the format the report is rendered in, see OutputFormat.
+	Output OutputFormat
+
+	// Watch, when set, replaces the one-shot checkup with a continuous
+	// watch driven by shared informers.
+	Watch bool
+
+	// Serve, when non-empty alongside Watch, serves the live report
+	// over HTTP on this address (e.g. ":8080") instead of rendering a
+	// TTY view.
+	Serve string
+
+	// ProblemsFile, when non-empty, is the path to a YAML file of
+	// declaratively-defined, CEL-based problems to register alongside
+	// the built-in ones.
+	ProblemsFile string
 }
 
 // NewOptions contains options for the devenv debug
@@ -66,11 +110,63 @@ func NewCommand(log logrus.FieldLogger) *cli.Command {
 		// Edited Name and Usage of command
 		Name:  "checkup",
 		Usage: "Debug Kubernetes clusters",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "fix",
+				Usage: "attempt to remediate detected problems (deletes/evicts affected resources)",
+			},
+			&cli.DurationFlag{
+				Name:  "grace-period",
+				Usage: "grace period given to resources when remediating with --fix",
+				Value: 30 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:  "dump",
+				Usage: "write a diagnostics bundle (logs, exec probes, report.json) for detected problems to this directory",
+			},
+			&cli.StringFlag{
+				Name:  "dump-exec-cmd",
+				Usage: "command run in-pod to probe not-ready pods when --dump is set",
+				Value: "ps aux",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "output format: text, json, yaml or sarif",
+				Value:   string(OutputFormatText),
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "continuously watch the cluster via informers instead of a one-shot check",
+			},
+			&cli.StringFlag{
+				Name:  "serve",
+				Usage: "with --watch, serve the live report over HTTP on this address (e.g. :8080) instead of a TTY view",
+			},
+			&cli.StringFlag{
+				Name:  "problems",
+				Usage: "path to a YAML file of declaratively-defined, CEL-based problems to check for in addition to the built-in ones",
+			},
+		},
 		Action: func(c *cli.Context) error {
 			if c.NArg() != 0 {
 				return fmt.Errorf("this command takes no arguments")
 			}
 
+			format, err := parseOutputFormat(c.String("output"))
+			if err != nil {
+				return err
+			}
+
+			o.Fix = c.Bool("fix")
+			o.GracePeriod = c.Duration("grace-period")
+			o.Dump = c.String("dump")
+			o.DumpExecCmd = c.String("dump-exec-cmd")
+			o.Output = format
+			o.Watch = c.Bool("watch")
+			o.Serve = c.String("serve")
+			o.ProblemsFile = c.String("problems")
+
 			return o.Run(c.Context)
 		},
 	}
@@ -103,66 +199,76 @@ type ResourceProblem struct {
 	Problem Problem
 }
 
-// getPodsWithProblems creates a list of problems i/r/t pods
-func (o *Options) getPodsWithProblems(ctx context.Context, pod *corev1.Pod) ([]Resource, bool) {
-	problems := make([]Resource, 0)
-
-	// defaultProblem is a problem that for the pod with prefilled
-	// information, use this when you create a problem for a pod
-	defaultProblem := Resource{
-		Owner: pod.Labels["reporting_team"],
-		Name:  fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
-		Type:  "pod",
+// Run runs the devenv debug command
+func (o *Options) Run(ctx context.Context) error { //nolint:funlen // Why: Best we can get currently
+	//nolint:errcheck // Why: We handle errors
+	k, err := kube.GetKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to get kubernetes client (is the devenv running?)")
 	}
 
-	// check if the pod has a problem from the enabled problems
-	for _, problem := range enabledProblems {
-		resourceDetails, warning, occurring := problem.Detector(ctx, pod)
-		if !occurring {
-			continue
+	if o.ProblemsFile != "" {
+		problems, err := LoadProblems(o.ProblemsFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to load --problems config")
 		}
 
-		p := defaultProblem
-		p.ProblemID = problem.ID
-		p.ProblemDetails = resourceDetails
-		p.Warning = warning
-		problems = append(problems, p)
+		for _, p := range problems {
+			RegisterProblem(p)
+		}
 	}
 
-	return problems, len(problems) > 0
-}
+	if o.Watch {
+		return o.runWatch(ctx, k)
+	}
 
-// Run runs the devenv debug command
-func (o *Options) Run(ctx context.Context) error { //nolint:funlen // Why: Best we can get currently
-	//nolint:errcheck // Why: We handle errors
-	k, err := kube.GetKubeClient()
-	if err != nil {
-		return errors.Wrap(err, "failed to get kubernetes client (is the devenv running?)")
+	// Progress messages go to stderr for structured output formats, so
+	// stdout holds only the serialized report.
+	progress := io.Writer(os.Stdout)
+	if o.Output != OutputFormatText {
+		progress = os.Stderr
 	}
 
-	pods, err := k.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	bold.Fprintf(progress, "Checking for problems ... ")
+	resourceProblems, err := collectAll(ctx, o.reporters(k))
 	if err != nil {
-		return errors.Wrap(err, "failed to list pods")
+		return errors.Wrap(err, "failed to collect cluster problems")
 	}
+	bold.Fprintln(progress, "done")
+
+	report := ReportFromResources(resourceProblems)
 
-	bold.Printf("Checking for problems ... ")
-	resourceProblems := []Resource{}
-	for i := range pods.Items {
-		p := &pods.Items[i]
-		if rs, is := o.getPodsWithProblems(ctx, p); is {
-			resourceProblems = append(resourceProblems, rs...)
+	if o.Dump != "" {
+		if err := dumpProblems(ctx, k, report, resourceProblems, o.Dump, o.DumpExecCmd); err != nil {
+			return errors.Wrap(err, "failed to write diagnostics bundle")
 		}
+		fmt.Fprintf(progress, "📦  Diagnostics bundle written to %s\n", o.Dump)
 	}
-	bold.Println("done")
+
+	if o.Output != OutputFormatText {
+		if err := writeReport(os.Stdout, o.Output, report); err != nil {
+			return errors.Wrap(err, "failed to write report")
+		}
+
+		return o.finish(ctx, k, resourceProblems)
+	}
+
+	renderText(report, resourceProblems)
+
+	return o.finish(ctx, k, resourceProblems)
+}
+
+// renderText prints the colorized, human-readable rendering of a report
+// used by the default text output mode and by watch mode's TTY view.
+func renderText(report *Report, resourceProblems []Resource) {
 	if len(resourceProblems) == 0 {
 		fmt.Println("Everything looks good 🎉")
-		return nil
+		return
 	}
 
 	fmt.Println("")
 	bold.Println("⛔️  Problems found (format: namespace/name <problem>):")
 
-	report := ReportFromResources(resourceProblems)
 	byProblem := report.ByProblem()
 	bySeverity := report.BySeverity()
 
@@ -228,8 +334,27 @@ func (o *Options) Run(ctx context.Context) error { //nolint:funlen // Why: Best
 		fmt.Fprintln(tw, "    -", bold.Sprint(id)+":\t", underline.Sprintf(helpURL))
 	}
 	tw.Flush()
+}
 
-	os.Exit(1)
+// finish runs the post-report steps common to every output format
+// (remediation, and reporting whether any problems were found) and
+// returns ErrProblemsFound instead of hard-exiting, so callers/tests can
+// consume the result.
+func (o *Options) finish(ctx context.Context, k kubernetes.Interface, resourceProblems []Resource) error {
+	if o.Fix {
+		// Structured output formats go to stdout, so the human-readable
+		// remediation summary has to go to stderr instead, or it'd corrupt
+		// the payload for callers parsing -o json/yaml/sarif.
+		w := io.Writer(os.Stdout)
+		if o.Output != OutputFormatText {
+			w = os.Stderr
+		}
+		printRemediationSummary(w, remediate(ctx, k, resourceProblems, o.GracePeriod))
+	}
+
+	if len(resourceProblems) > 0 {
+		return ErrProblemsFound
+	}
 
 	return nil
 }
@@ -0,0 +1,155 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the `--dump` diagnostics bundle,
+// which pulls previous-container logs and runs an in-pod exec probe for
+// detected pod problems so users have something to attach to a bug
+// report.
+
+package debug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// dumpProblems writes a diagnostics bundle to dir: previous-container
+// logs for crash loops/OOM kills (one file per restarted container), an
+// in-pod exec probe for not-ready pods (one file per pod), plus a
+// report.json capturing the full report. Other problems, including every
+// non-pod one, get no file of their own.
+func dumpProblems(ctx context.Context, k kubernetes.Interface, report *Report, resources []Resource, dir, execCmd string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrap(err, "failed to create dump directory")
+	}
+
+	restConfig, err := restConfigForDump()
+	if err != nil {
+		return errors.Wrap(err, "failed to load kubeconfig for exec probe")
+	}
+
+	for _, r := range resources {
+		if err := dumpResource(ctx, k, restConfig, r, dir, execCmd); err != nil {
+			return errors.Wrapf(err, "failed to dump %s", r.Name)
+		}
+	}
+
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal report")
+	}
+
+	return os.WriteFile(filepath.Join(dir, "report.json"), reportJSON, 0o644) //nolint:gosec // Why: diagnostics bundle, not sensitive
+}
+
+// dumpResource writes the diagnostics relevant to a single problem
+// resource's problem ID.
+func dumpResource(ctx context.Context, k kubernetes.Interface, restConfig *rest.Config, r Resource, dir, execCmd string) error {
+	if r.Type != "pod" {
+		return nil
+	}
+
+	namespace, name, ok := strings.Cut(r.Name, "/")
+	if !ok {
+		return errors.Errorf("invalid pod resource name %q", r.Name)
+	}
+
+	switch r.ProblemID {
+	case ProblemPodCrashLoopBackOff.ID, ProblemPodOOMKilled.ID:
+		return dumpPreviousLogs(ctx, k, namespace, name, dir)
+	case ProblemPodNotReady.ID:
+		return dumpExecProbe(ctx, k, restConfig, namespace, name, execCmd, dir)
+	}
+
+	return nil
+}
+
+// dumpPreviousLogs writes the previous container's logs for every
+// container in the pod that has restarted.
+func dumpPreviousLogs(ctx context.Context, k kubernetes.Interface, namespace, name, dir string) error {
+	pod, err := k.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get pod")
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.RestartCount == 0 {
+			continue
+		}
+
+		logs, err := k.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
+			Previous:  true,
+			Container: cs.Name,
+		}).Stream(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to stream previous logs for %s", cs.Name)
+		}
+
+		var buf bytes.Buffer
+		_, copyErr := io.Copy(&buf, logs)
+		logs.Close()
+		if copyErr != nil {
+			return errors.Wrapf(copyErr, "failed to read previous logs for %s", cs.Name)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%s_%s_%s.previous.log", namespace, name, cs.Name))
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil { //nolint:gosec // Why: diagnostics bundle, not sensitive
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dumpExecProbe runs execCmd inside the pod via a SPDY exec stream and
+// writes its combined stdout/stderr to dir.
+func dumpExecProbe(ctx context.Context, k kubernetes.Interface, restConfig *rest.Config, namespace, name, execCmd, dir string) error {
+	req := k.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command: strings.Fields(execCmd),
+			Stdout:  true,
+			Stderr:  true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return errors.Wrap(err, "failed to create SPDY executor")
+	}
+
+	var buf bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &buf,
+		Stderr: &buf,
+	}); err != nil {
+		fmt.Fprintf(&buf, "\n[exec error] %s\n", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.exec.log", namespace, name))
+	return os.WriteFile(path, buf.Bytes(), 0o644) //nolint:gosec // Why: diagnostics bundle, not sensitive
+}
+
+// restConfigForDump loads the ambient kubeconfig, the same way kubectl
+// does, for use with the raw SPDY exec client.
+func restConfigForDump() (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+}
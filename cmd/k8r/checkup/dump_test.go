@@ -0,0 +1,108 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: Tests for the --dump diagnostics bundle's per-problem-ID
+// dispatch.
+
+package debug
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDumpResourceSkipsNonPodResources(t *testing.T) {
+	dir := t.TempDir()
+	client := fake.NewSimpleClientset()
+
+	r := Resource{Name: "my-deployment", Type: "deployment", ProblemID: ProblemDeploymentReplicasUnavailable.ID}
+	if err := dumpResource(context.Background(), client, nil, r, dir, "ps aux"); err != nil {
+		t.Fatalf("dumpResource() error = %v, want nil for a non-pod resource", err)
+	}
+}
+
+func TestDumpResourceRejectsMalformedPodName(t *testing.T) {
+	dir := t.TempDir()
+	client := fake.NewSimpleClientset()
+
+	r := Resource{Name: "not-namespaced", Type: "pod", ProblemID: ProblemPodCrashLoopBackOff.ID}
+	if err := dumpResource(context.Background(), client, nil, r, dir, "ps aux"); err == nil {
+		t.Fatal("dumpResource() error = nil, want an error for a pod name without a namespace")
+	}
+}
+
+func TestDumpResourceDispatchesCrashLoopAndOOMToPreviousLogs(t *testing.T) {
+	for _, problemID := range []string{ProblemPodCrashLoopBackOff.ID, ProblemPodOOMKilled.ID} {
+		t.Run(problemID, func(t *testing.T) {
+			dir := t.TempDir()
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 0}},
+				},
+			}
+			client := fake.NewSimpleClientset(pod)
+
+			r := Resource{Name: "default/pod-1", Type: "pod", ProblemID: problemID}
+			if err := dumpResource(context.Background(), client, nil, r, dir, "ps aux"); err != nil {
+				t.Fatalf("dumpResource() error = %v, want nil", err)
+			}
+
+			// No container has restarted, so no previous-log file should
+			// have been written, and the call shouldn't have reached the
+			// (un-fakeable) log-streaming path.
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("os.ReadDir() error = %v", err)
+			}
+			if len(entries) != 0 {
+				t.Fatalf("dumpResource() wrote %d file(s), want 0: %v", len(entries), entries)
+			}
+		})
+	}
+}
+
+func TestDumpResourceIgnoresUnknownProblemID(t *testing.T) {
+	dir := t.TempDir()
+	client := fake.NewSimpleClientset()
+
+	r := Resource{Name: "default/pod-1", Type: "pod", ProblemID: "SomeOtherProblem"}
+	if err := dumpResource(context.Background(), client, nil, r, dir, "ps aux"); err != nil {
+		t.Fatalf("dumpResource() error = %v, want nil for an unhandled problem ID", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("os.ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("dumpResource() wrote %d file(s), want 0: %v", len(entries), entries)
+	}
+}
+
+func TestDumpPreviousLogsSkipsContainersWithNoRestarts(t *testing.T) {
+	dir := t.TempDir()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", RestartCount: 0},
+				{Name: "sidecar", RestartCount: 0},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(pod)
+
+	if err := dumpPreviousLogs(context.Background(), client, "default", "pod-1", dir); err != nil {
+		t.Fatalf("dumpPreviousLogs() error = %v, want nil", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "default_pod-1_app.previous.log")); !os.IsNotExist(err) {
+		t.Fatalf("expected no previous-log file to be written, stat error = %v", err)
+	}
+}
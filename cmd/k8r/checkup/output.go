@@ -0,0 +1,180 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the structured (JSON/YAML/SARIF)
+// output modes for the checkup report, used by `-o`.
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// OutputFormat is a supported serialization format for `-o`.
+type OutputFormat string
+
+// Supported output formats.
+const (
+	OutputFormatText  OutputFormat = "text"
+	OutputFormatJSON  OutputFormat = "json"
+	OutputFormatYAML  OutputFormat = "yaml"
+	OutputFormatSARIF OutputFormat = "sarif"
+)
+
+// parseOutputFormat validates and normalizes the value of `-o`.
+func parseOutputFormat(s string) (OutputFormat, error) {
+	switch OutputFormat(s) {
+	case "", OutputFormatText:
+		return OutputFormatText, nil
+	case OutputFormatJSON, OutputFormatYAML, OutputFormatSARIF:
+		return OutputFormat(s), nil
+	default:
+		return "", errors.Errorf("unknown output format %q, must be one of: text, json, yaml, sarif", s)
+	}
+}
+
+// writeReport serializes report in the given format to w.
+func writeReport(w io.Writer, format OutputFormat, report *Report) error {
+	switch format {
+	case OutputFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case OutputFormatYAML:
+		b, err := json.Marshal(report)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal report")
+		}
+
+		y, err := yaml.JSONToYAML(b)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert report to yaml")
+		}
+
+		_, err = w.Write(y)
+		return err
+	case OutputFormatSARIF:
+		return json.NewEncoder(w).Encode(reportToSARIF(report))
+	default:
+		return errors.Errorf("unsupported output format %q", format)
+	}
+}
+
+// SARIF types below implement the subset of the SARIF 2.1.0 schema
+// (https://docs.oasis-open.org/sarif/sarif/v2.1.0) that checkup needs:
+// one rule per Problem and one result per ResourceProblem.
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                  `json:"id"`
+	ShortDescription sarifMultiformatMessage `json:"shortDescription"`
+	HelpURI          string                  `json:"helpUri,omitempty"`
+}
+
+type sarifMultiformatMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string                  `json:"ruleId"`
+	Level     string                  `json:"level"`
+	Message   sarifMultiformatMessage `json:"message"`
+	Locations []sarifLocation         `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// reportToSARIF converts a Report into a SARIF log with one rule per
+// Problem and one result per ResourceProblem, so checkup output can be
+// piped into code-scanning dashboards.
+func reportToSARIF(report *Report) sarifLog {
+	byProblem := report.ByProblem()
+
+	rules := make([]sarifRule, 0, len(byProblem))
+	results := make([]sarifResult, 0)
+	for id, resources := range byProblem {
+		p := report.GetProblemByID(id)
+		if p == nil {
+			continue
+		}
+
+		rules = append(rules, sarifRule{
+			ID:               p.ID,
+			ShortDescription: sarifMultiformatMessage{Text: p.ShortDescription},
+			HelpURI:          p.HelpURL,
+		})
+
+		for _, r := range resources {
+			level := "error"
+			if r.Warning {
+				level = "warning"
+			}
+
+			results = append(results, sarifResult{
+				RuleID:  p.ID,
+				Level:   level,
+				Message: sarifMultiformatMessage{Text: r.ProblemDetails},
+				Locations: []sarifLocation{
+					{
+						LogicalLocations: []sarifLogicalLocation{
+							{FullyQualifiedName: r.Name, Kind: r.Type},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "k8r-checkup",
+						InformationURI: "https://github.com/Ashvin-Ranjan/k7r",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+// ErrProblemsFound is returned by Run when the checkup finds at least
+// one problem, so callers can distinguish "found problems" from an
+// actual failure to run the checkup.
+var ErrProblemsFound = fmt.Errorf("problems were found during checkup")
@@ -0,0 +1,92 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: Tests for the structured (JSON/YAML/SARIF) output modes.
+
+package debug
+
+import "testing"
+
+func TestParseOutputFormat(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{in: "", want: OutputFormatText},
+		{in: "text", want: OutputFormatText},
+		{in: "json", want: OutputFormatJSON},
+		{in: "yaml", want: OutputFormatYAML},
+		{in: "sarif", want: OutputFormatSARIF},
+		{in: "xml", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseOutputFormat(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseOutputFormat(%q) error = nil, want an error", tc.in)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseOutputFormat(%q) error = %v, want nil", tc.in, err)
+			}
+			if got != tc.want {
+				t.Fatalf("parseOutputFormat(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReportToSARIF(t *testing.T) {
+	resources := []Resource{
+		{
+			Name:           "default/pod-1",
+			Type:           "pod",
+			ProblemID:      ProblemPodCrashLoopBackOff.ID,
+			ProblemDetails: "container is crash looping",
+			Warning:        false,
+		},
+		{
+			Name:           "default/pod-2",
+			Type:           "pod",
+			ProblemID:      ProblemPodOOMKilled.ID,
+			ProblemDetails: "container was OOM killed",
+			Warning:        true,
+		},
+	}
+
+	sarif := reportToSARIF(ReportFromResources(resources))
+
+	if len(sarif.Runs) != 1 {
+		t.Fatalf("len(sarif.Runs) = %d, want 1", len(sarif.Runs))
+	}
+
+	run := sarif.Runs[0]
+	if len(run.Results) != len(resources) {
+		t.Fatalf("len(run.Results) = %d, want %d", len(run.Results), len(resources))
+	}
+
+	rules := make(map[string]bool)
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.ID] = true
+	}
+	for _, r := range resources {
+		if !rules[r.ProblemID] {
+			t.Errorf("expected a SARIF rule for %s", r.ProblemID)
+		}
+	}
+
+	levels := make(map[string]string)
+	for _, res := range run.Results {
+		levels[res.Message.Text] = res.Level
+	}
+	if got := levels["container is crash looping"]; got != "error" {
+		t.Errorf("level for non-warning result = %q, want %q", got, "error")
+	}
+	if got := levels["container was OOM killed"]; got != "warning" {
+		t.Errorf("level for warning result = %q, want %q", got, "warning")
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the Remediator interface and the
+// machinery used to run remediations for `checkup --fix`.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Remediator performs a scoped remediation for a single resource that
+// was flagged by a Problem.
+type Remediator interface {
+	// Remediate attempts to fix the given resource. r.Name is of the
+	// form "namespace/name" for namespaced resources, or just "name"
+	// for cluster-scoped ones (e.g. nodes).
+	Remediate(ctx context.Context, k kubernetes.Interface, r Resource, gracePeriod time.Duration) error
+}
+
+// remediators maps a Problem ID to the Remediator that knows how to fix
+// it. Not every problem has one.
+var remediators = map[string]Remediator{}
+
+// RegisterRemediator associates a Remediator with a Problem ID, so that
+// `checkup --fix` knows how to act on resources reporting that problem.
+func RegisterRemediator(problemID string, rem Remediator) {
+	remediators[problemID] = rem
+}
+
+func init() {
+	RegisterRemediator(ProblemPodCrashLoopBackOff.ID, &PodDeleteRemediator{})
+	RegisterRemediator(ProblemNodeNotReady.ID, &NodeDrainRemediator{})
+	RegisterRemediator(ProblemNodeMemoryPressure.ID, &NodeDrainRemediator{})
+	RegisterRemediator(ProblemNodeDiskPressure.ID, &NodeDrainRemediator{})
+}
+
+// RemediationResult is the outcome of attempting to remediate a single
+// resource.
+type RemediationResult struct {
+	Resource Resource
+	Err      error
+}
+
+// remediate attempts to fix every resource that has a registered
+// Remediator for its problem, returning one result per attempt made.
+func remediate(ctx context.Context, k kubernetes.Interface, resources []Resource, gracePeriod time.Duration) []RemediationResult {
+	results := make([]RemediationResult, 0)
+	for _, res := range resources {
+		rem, ok := remediators[res.ProblemID]
+		if !ok {
+			continue
+		}
+
+		results = append(results, RemediationResult{
+			Resource: res,
+			Err:      rem.Remediate(ctx, k, res, gracePeriod),
+		})
+	}
+
+	return results
+}
+
+// printRemediationSummary prints a per-resource pass/fail summary of a
+// remediation run to w. Callers writing a structured report (-o
+// json/yaml/sarif) to stdout should pass os.Stderr here instead, so the
+// human-readable summary doesn't corrupt the machine-readable payload.
+func printRemediationSummary(w io.Writer, results []RemediationResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	bold.Fprintln(w, "🔧  Remediation summary:")
+	tw := tabwriter.NewWriter(w, 1, 0, 1, ' ', 0)
+	for _, res := range results {
+		status := color.HiGreenString("fixed")
+		if res.Err != nil {
+			status = color.HiRedString("failed: %s", res.Err)
+		}
+		fmt.Fprintln(tw, "    -", bold.Sprint(res.Resource.Name)+":\t"+status)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the NodeDrainRemediator.
+
+package debug
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// evictionRetryTimeout bounds how long the remediator keeps retrying an
+// eviction that a PodDisruptionBudget is blocking before giving up and
+// falling back to a plain delete, mirroring `kubectl drain`.
+const evictionRetryTimeout = 2 * time.Minute
+
+// evictionRetryInterval is how long evictOrDelete waits between retries
+// of a PodDisruptionBudget-blocked eviction.
+const evictionRetryInterval = 5 * time.Second
+
+// NodeDrainRemediator cordons a problem node and evicts its pods. It
+// prefers the eviction API, so PodDisruptionBudgets are respected, and
+// falls back to deleting a pod outright if eviction keeps getting
+// rejected with a 429 or PDB conflict, mirroring how `kubectl drain`
+// handles eviction.
+type NodeDrainRemediator struct{}
+
+// Remediate implements Remediator.
+func (n *NodeDrainRemediator) Remediate(ctx context.Context, k kubernetes.Interface, r Resource, gracePeriod time.Duration) error {
+	if err := cordon(ctx, k, r.Name); err != nil {
+		return errors.Wrap(err, "failed to cordon node")
+	}
+
+	pods, err := k.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + r.Name,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list pods on node")
+	}
+
+	var failed []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if err := evictOrDelete(ctx, k, pod, gracePeriod, evictionRetryTimeout, evictionRetryInterval); err != nil {
+			failed = append(failed, errors.Wrapf(err, "%s/%s", pod.Namespace, pod.Name).Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to evict %d of %d pod(s): %s", len(failed), len(pods.Items), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// cordon marks a node as unschedulable.
+func cordon(ctx context.Context, k kubernetes.Interface, name string) error {
+	node, err := k.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = true
+	_, err = k.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
+}
+
+// evictOrDelete evicts a pod via the eviction API, retrying every
+// retryInterval while a PodDisruptionBudget is blocking it, and falling
+// back to a plain delete once timeout has elapsed.
+func evictOrDelete(ctx context.Context, k kubernetes.Interface, pod *corev1.Pod, gracePeriod, timeout, retryInterval time.Duration) error {
+	seconds := int64(gracePeriod.Seconds())
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &metav1.DeleteOptions{
+			GracePeriodSeconds: &seconds,
+		},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := k.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+			return nil
+		case apierrors.IsTooManyRequests(err):
+			if time.Now().After(deadline) {
+				return k.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+					GracePeriodSeconds: &seconds,
+				})
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryInterval):
+			}
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: Tests for evictOrDelete's retry/fallback state machine.
+
+package debug
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestPod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func TestEvictOrDeleteSucceedsImmediately(t *testing.T) {
+	pod := newTestPod("default", "pod-1")
+	client := fake.NewSimpleClientset(pod)
+
+	if err := evictOrDelete(context.Background(), client, pod, time.Second, time.Minute, time.Millisecond); err != nil {
+		t.Fatalf("evictOrDelete() error = %v, want nil", err)
+	}
+}
+
+// rejectEvictions makes every eviction request fail with 429, simulating
+// a PodDisruptionBudget that never allows the eviction through.
+func rejectEvictions(client *fake.Clientset) {
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewTooManyRequests("blocked by PodDisruptionBudget", 1)
+	})
+}
+
+func TestEvictOrDeleteFallsBackToDeleteAfterTimeout(t *testing.T) {
+	pod := newTestPod("default", "pod-2")
+	client := fake.NewSimpleClientset(pod)
+	rejectEvictions(client)
+
+	err := evictOrDelete(context.Background(), client, pod, 20*time.Millisecond, time.Minute, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("evictOrDelete() error = %v, want nil (fallback delete should succeed)", err)
+	}
+
+	_, getErr := client.CoreV1().Pods("default").Get(context.Background(), "pod-2", metav1.GetOptions{})
+	if !apierrors.IsNotFound(getErr) {
+		t.Fatalf("expected pod to have been deleted as a fallback, Get() error = %v", getErr)
+	}
+}
+
+func TestEvictOrDeleteHonorsContextCancellation(t *testing.T) {
+	pod := newTestPod("default", "pod-3")
+	client := fake.NewSimpleClientset(pod)
+	rejectEvictions(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := evictOrDelete(ctx, client, pod, time.Second, time.Minute, time.Millisecond); err == nil {
+		t.Fatal("evictOrDelete() error = nil, want context.Canceled")
+	}
+}
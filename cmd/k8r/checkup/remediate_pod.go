@@ -0,0 +1,32 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the PodDeleteRemediator.
+
+package debug
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodDeleteRemediator deletes a problem pod so that its owning
+// controller (Deployment, StatefulSet, etc.) recreates it.
+type PodDeleteRemediator struct{}
+
+// Remediate implements Remediator.
+func (p *PodDeleteRemediator) Remediate(ctx context.Context, k kubernetes.Interface, r Resource, gracePeriod time.Duration) error {
+	namespace, name, ok := strings.Cut(r.Name, "/")
+	if !ok {
+		return errors.Errorf("invalid pod resource name %q", r.Name)
+	}
+
+	seconds := int64(gracePeriod.Seconds())
+	return k.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: &seconds,
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the Reporter interface and the
+// machinery used to fan out over every registered reporter.
+
+package debug
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reporter collects a particular kind of resource from the cluster and
+// returns the ones that have a problem associated with them. Each
+// resource kind the checkup understands (pods, deployments, etc.)
+// implements this so Run can treat them uniformly.
+type Reporter interface {
+	// Collect gathers resources from the cluster and returns the ones
+	// found to have a problem.
+	Collect(ctx context.Context) ([]Resource, error)
+}
+
+// reporters returns the set of reporters that Run fans out over to build
+// the full cluster checkup report.
+func (o *Options) reporters(k kubernetes.Interface) []Reporter {
+	return []Reporter{
+		&PodReporter{Client: k},
+		&DeploymentReporter{Client: k},
+		&StatefulSetReporter{Client: k},
+		&NodeReporter{Client: k},
+	}
+}
+
+// collectAll runs every reporter concurrently and merges their results
+// into a single slice of resources, ready to be passed to
+// ReportFromResources.
+func collectAll(ctx context.Context, reporters []Reporter) ([]Resource, error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		resources = make([]Resource, 0)
+		errs      []error
+	)
+
+	wg.Add(len(reporters))
+	for _, rep := range reporters {
+		go func(rep Reporter) {
+			defer wg.Done()
+
+			rs, err := rep.Collect(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			resources = append(resources, rs...)
+		}(rep)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return resources, errors.Errorf("%d reporter(s) failed: %v", len(errs), errs)
+	}
+
+	return resources, nil
+}
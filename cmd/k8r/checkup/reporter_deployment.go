@@ -0,0 +1,72 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the DeploymentReporter, which
+// collects problems i/r/t Deployments.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ProblemDeploymentReplicasUnavailable is reported when a Deployment has
+// fewer available replicas than it was asked to run.
+var ProblemDeploymentReplicasUnavailable = Problem{
+	ID:               "DeploymentReplicasUnavailable",
+	ShortDescription: "Deployment has unavailable replicas",
+}
+
+func init() {
+	RegisterProblem(ProblemDeploymentReplicasUnavailable)
+}
+
+// DeploymentReporter collects problems i/r/t Deployments, e.g. ones that
+// can't get all of their replicas healthy.
+type DeploymentReporter struct {
+	// Client is the Kubernetes client used to list deployments.
+	Client kubernetes.Interface
+}
+
+// Collect implements Reporter.
+func (r *DeploymentReporter) Collect(ctx context.Context) ([]Resource, error) {
+	deployments, err := r.Client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list deployments")
+	}
+
+	resources := make([]Resource, 0)
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if res, is := deploymentProblem(d); is {
+			resources = append(resources, res)
+		}
+	}
+
+	return resources, nil
+}
+
+// deploymentProblem checks a single Deployment for problems.
+func deploymentProblem(d *appsv1.Deployment) (Resource, bool) {
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+
+	if d.Status.AvailableReplicas >= desired {
+		return Resource{}, false
+	}
+
+	return Resource{
+		Owner:          d.Labels["reporting_team"],
+		Name:           fmt.Sprintf("%s/%s", d.Namespace, d.Name),
+		Type:           "deployment",
+		ProblemID:      ProblemDeploymentReplicasUnavailable.ID,
+		ProblemDetails: fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, desired),
+	}, true
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the NodeReporter, which collects
+// problems i/r/t Nodes.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Problem definitions for node-level conditions.
+var (
+	// ProblemNodeMemoryPressure is reported when a node is under memory
+	// pressure.
+	ProblemNodeMemoryPressure = Problem{
+		ID:               "NodeMemoryPressure",
+		ShortDescription: "Node is under memory pressure",
+	}
+
+	// ProblemNodeDiskPressure is reported when a node is under disk
+	// pressure.
+	ProblemNodeDiskPressure = Problem{
+		ID:               "NodeDiskPressure",
+		ShortDescription: "Node is under disk pressure",
+	}
+
+	// ProblemNodeNotReady is reported when a node's Ready condition is
+	// not True.
+	ProblemNodeNotReady = Problem{
+		ID:               "NodeNotReady",
+		ShortDescription: "Node is not ready",
+	}
+)
+
+func init() {
+	RegisterProblem(ProblemNodeMemoryPressure)
+	RegisterProblem(ProblemNodeDiskPressure)
+	RegisterProblem(ProblemNodeNotReady)
+}
+
+// NodeReporter collects problems i/r/t Nodes, e.g. nodes that are under
+// resource pressure or not ready.
+type NodeReporter struct {
+	// Client is the Kubernetes client used to list nodes.
+	Client kubernetes.Interface
+}
+
+// Collect implements Reporter.
+func (r *NodeReporter) Collect(ctx context.Context) ([]Resource, error) {
+	nodes, err := r.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	resources := make([]Resource, 0)
+	for i := range nodes.Items {
+		resources = append(resources, nodeProblems(&nodes.Items[i])...)
+	}
+
+	return resources, nil
+}
+
+// nodeProblems checks a single Node's conditions for problems.
+func nodeProblems(node *corev1.Node) []Resource {
+	problems := make([]Resource, 0)
+
+	defaultResource := Resource{
+		Owner: node.Labels["reporting_team"],
+		Name:  node.Name,
+		Type:  "node",
+	}
+
+	for _, cond := range node.Status.Conditions {
+		switch {
+		case cond.Type == corev1.NodeMemoryPressure && cond.Status == corev1.ConditionTrue:
+			r := defaultResource
+			r.ProblemID = ProblemNodeMemoryPressure.ID
+			r.ProblemDetails = cond.Message
+			problems = append(problems, r)
+		case cond.Type == corev1.NodeDiskPressure && cond.Status == corev1.ConditionTrue:
+			r := defaultResource
+			r.ProblemID = ProblemNodeDiskPressure.ID
+			r.ProblemDetails = cond.Message
+			problems = append(problems, r)
+		case cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue:
+			r := defaultResource
+			r.ProblemID = ProblemNodeNotReady.ID
+			r.ProblemDetails = fmt.Sprintf("ready condition is %s: %s", cond.Status, cond.Message)
+			problems = append(problems, r)
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the PodReporter, which collects
+// pod-level problems such as crash loops and image pull failures.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodReporter collects problems i/r/t pods, e.g. pods that are crash
+// looping or stuck pulling their image.
+type PodReporter struct {
+	// Client is the Kubernetes client used to list pods.
+	Client kubernetes.Interface
+}
+
+// Collect implements Reporter.
+func (r *PodReporter) Collect(ctx context.Context) ([]Resource, error) {
+	pods, err := r.Client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	resources := make([]Resource, 0)
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		if rs, is := podProblems(ctx, p); is {
+			resources = append(resources, rs...)
+		}
+	}
+
+	return resources, nil
+}
+
+// podProblems creates a list of problems i/r/t a single pod
+func podProblems(ctx context.Context, pod *corev1.Pod) ([]Resource, bool) {
+	problems := make([]Resource, 0)
+
+	// defaultProblem is a problem that for the pod with prefilled
+	// information, use this when you create a problem for a pod
+	defaultProblem := Resource{
+		Owner: pod.Labels["reporting_team"],
+		Name:  fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
+		Type:  "pod",
+	}
+
+	// check if the pod has a problem from the enabled problems. Problems
+	// registered by other reporters (deployments, nodes, ...) don't have
+	// a pod Detector and are skipped here.
+	for _, problem := range enabledProblems {
+		if problem.Detector == nil {
+			continue
+		}
+
+		resourceDetails, warning, occurring := problem.Detector(ctx, pod)
+		if !occurring {
+			continue
+		}
+
+		p := defaultProblem
+		p.ProblemID = problem.ID
+		p.ProblemDetails = resourceDetails
+		p.Warning = warning
+		problems = append(problems, p)
+	}
+
+	return problems, len(problems) > 0
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains the StatefulSetReporter, which
+// collects problems i/r/t StatefulSets.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ProblemStatefulSetRolloutStuck is reported when a StatefulSet's rollout
+// hasn't finished updating all of its replicas to the latest revision.
+var ProblemStatefulSetRolloutStuck = Problem{
+	ID:               "StatefulSetRolloutStuck",
+	ShortDescription: "StatefulSet rollout is not progressing",
+}
+
+func init() {
+	RegisterProblem(ProblemStatefulSetRolloutStuck)
+}
+
+// StatefulSetReporter collects problems i/r/t StatefulSets, e.g. ones
+// whose rollout is stuck partway through.
+type StatefulSetReporter struct {
+	// Client is the Kubernetes client used to list statefulsets.
+	Client kubernetes.Interface
+}
+
+// Collect implements Reporter.
+func (r *StatefulSetReporter) Collect(ctx context.Context) ([]Resource, error) {
+	statefulSets, err := r.Client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list statefulsets")
+	}
+
+	resources := make([]Resource, 0)
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		if res, is := statefulSetProblem(s); is {
+			resources = append(resources, res)
+		}
+	}
+
+	return resources, nil
+}
+
+// statefulSetProblem checks a single StatefulSet for a stuck rollout.
+func statefulSetProblem(s *appsv1.StatefulSet) (Resource, bool) {
+	desired := int32(1)
+	if s.Spec.Replicas != nil {
+		desired = *s.Spec.Replicas
+	}
+
+	rolloutStuck := s.Status.UpdatedReplicas < desired &&
+		s.Status.CurrentRevision != "" &&
+		s.Status.CurrentRevision != s.Status.UpdateRevision
+	if !rolloutStuck {
+		return Resource{}, false
+	}
+
+	return Resource{
+		Owner:     s.Labels["reporting_team"],
+		Name:      fmt.Sprintf("%s/%s", s.Namespace, s.Name),
+		Type:      "statefulset",
+		ProblemID: ProblemStatefulSetRolloutStuck.ID,
+		ProblemDetails: fmt.Sprintf("%d/%d replicas updated to revision %s",
+			s.Status.UpdatedReplicas, desired, s.Status.UpdateRevision),
+	}, true
+}
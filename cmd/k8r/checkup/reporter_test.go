@@ -0,0 +1,168 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: Tests for the per-resource problem detection used by
+// DeploymentReporter, StatefulSetReporter and NodeReporter.
+
+package debug
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestDeploymentProblem(t *testing.T) {
+	cases := []struct {
+		name string
+		dep  *appsv1.Deployment
+		want bool
+	}{
+		{
+			name: "healthy",
+			dep: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.DeploymentStatus{AvailableReplicas: 3},
+			},
+			want: false,
+		},
+		{
+			name: "unavailable replicas",
+			dep: &appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, is := deploymentProblem(tc.dep)
+			if is != tc.want {
+				t.Fatalf("deploymentProblem() occurring = %v, want %v", is, tc.want)
+			}
+			if is && res.ProblemID != ProblemDeploymentReplicasUnavailable.ID {
+				t.Fatalf("deploymentProblem() ProblemID = %q, want %q", res.ProblemID, ProblemDeploymentReplicasUnavailable.ID)
+			}
+		})
+	}
+}
+
+func TestStatefulSetProblem(t *testing.T) {
+	cases := []struct {
+		name string
+		sts  *appsv1.StatefulSet
+		want bool
+	}{
+		{
+			name: "healthy",
+			sts: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.StatefulSetStatus{
+					UpdatedReplicas: 3,
+					CurrentRevision: "rev-1",
+					UpdateRevision:  "rev-1",
+				},
+			},
+			want: false,
+		},
+		{
+			name: "rollout stuck",
+			sts: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+				Status: appsv1.StatefulSetStatus{
+					UpdatedReplicas: 1,
+					CurrentRevision: "rev-1",
+					UpdateRevision:  "rev-2",
+				},
+			},
+			want: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, is := statefulSetProblem(tc.sts)
+			if is != tc.want {
+				t.Fatalf("statefulSetProblem() occurring = %v, want %v", is, tc.want)
+			}
+			if is && res.ProblemID != ProblemStatefulSetRolloutStuck.ID {
+				t.Fatalf("statefulSetProblem() ProblemID = %q, want %q", res.ProblemID, ProblemStatefulSetRolloutStuck.ID)
+			}
+		})
+	}
+}
+
+func TestNodeProblems(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	problems := nodeProblems(node)
+
+	got := make(map[string]bool)
+	for _, p := range problems {
+		got[p.ProblemID] = true
+	}
+
+	if !got[ProblemNodeMemoryPressure.ID] {
+		t.Errorf("expected %s to be reported", ProblemNodeMemoryPressure.ID)
+	}
+	if got[ProblemNodeDiskPressure.ID] {
+		t.Errorf("did not expect %s to be reported", ProblemNodeDiskPressure.ID)
+	}
+	if !got[ProblemNodeNotReady.ID] {
+		t.Errorf("expected %s to be reported", ProblemNodeNotReady.ID)
+	}
+}
+
+// TestEnabledProblemsRegistration guards against the regression where
+// non-pod Problems (registered by the Deployment/StatefulSet/Node
+// reporters) were never added to the registry that report rendering
+// reads from.
+func TestEnabledProblemsRegistration(t *testing.T) {
+	want := []string{
+		ProblemDeploymentReplicasUnavailable.ID,
+		ProblemStatefulSetRolloutStuck.ID,
+		ProblemNodeMemoryPressure.ID,
+		ProblemNodeDiskPressure.ID,
+		ProblemNodeNotReady.ID,
+	}
+
+	registered := make(map[string]bool)
+	for _, p := range enabledProblems {
+		registered[p.ID] = true
+	}
+
+	for _, id := range want {
+		if !registered[id] {
+			t.Errorf("expected %s to be registered in enabledProblems", id)
+		}
+	}
+}
+
+// TestPodProblemsSkipsProblemsWithoutDetector guards against a panic
+// when enabledProblems contains a Problem with no Detector (e.g. the
+// Deployment/StatefulSet/Node ones, which are only ever detected by
+// their own reporter).
+func TestPodProblemsSkipsProblemsWithoutDetector(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"},
+	}
+
+	// Must not panic even though enabledProblems now contains Problems
+	// with a nil Detector.
+	podProblems(context.Background(), pod) //nolint:errcheck // Why: exercising for a panic, not checking the result
+}
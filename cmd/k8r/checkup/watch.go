@@ -0,0 +1,295 @@
+// Copyright 2022 Outreach Corporation. All Rights Reserved.
+
+// Description: This file contains `checkup --watch`, which replaces the
+// one-shot List-based checkup with a continuous watch driven by shared
+// informers, maintaining a live in-memory Report.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// watchResyncPeriod is how often the informers' caches are resynced.
+const watchResyncPeriod = 30 * time.Second
+
+// ttyRefreshInterval is how often the TTY view of `checkup --watch` is
+// re-rendered.
+const ttyRefreshInterval = 2 * time.Second
+
+// liveReport tracks the current set of problems found across every
+// watched resource, keyed by a "kind/namespace/name" informer key so
+// that an update or delete can replace or clear just that resource's
+// problems without disturbing the rest.
+type liveReport struct {
+	mu        sync.RWMutex
+	resources map[string][]Resource
+}
+
+// newLiveReport creates an empty liveReport.
+func newLiveReport() *liveReport {
+	return &liveReport{resources: make(map[string][]Resource)}
+}
+
+// set replaces the problems recorded for key, clearing it if resources
+// is empty.
+func (l *liveReport) set(key string, resources []Resource) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(resources) == 0 {
+		delete(l.resources, key)
+		return
+	}
+	l.resources[key] = resources
+}
+
+// snapshot returns every currently-recorded problem resource.
+func (l *liveReport) snapshot() []Resource {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	all := make([]Resource, 0)
+	for _, rs := range l.resources {
+		all = append(all, rs...)
+	}
+	return all
+}
+
+// runWatch replaces the one-shot checkup with informers on pods,
+// deployments, statefulsets and nodes, re-running the relevant problem
+// detector on every add/update and surfacing the result either as a
+// periodically re-rendered TTY view or an HTTP server.
+func (o *Options) runWatch(ctx context.Context, k kubernetes.Interface) error {
+	live := newLiveReport()
+
+	factory := informers.NewSharedInformerFactory(k, watchResyncPeriod)
+	if err := registerWatchHandlers(factory, live); err != nil {
+		return errors.Wrap(err, "failed to register watch handlers")
+	}
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	if o.Serve != "" {
+		return serveWatch(ctx, live, o.Serve)
+	}
+
+	return renderWatch(ctx, live)
+}
+
+// registerWatchHandlers wires up the informer event handlers that keep
+// live up to date for every watched resource kind.
+func registerWatchHandlers(factory informers.SharedInformerFactory, live *liveReport) error {
+	handlers := []struct {
+		informer cache.SharedIndexInformer
+		upsert   func(obj interface{})
+	}{
+		{factory.Core().V1().Pods().Informer(), func(obj interface{}) { upsertPod(live, obj) }},
+		{factory.Apps().V1().Deployments().Informer(), func(obj interface{}) { upsertDeployment(live, obj) }},
+		{factory.Apps().V1().StatefulSets().Informer(), func(obj interface{}) { upsertStatefulSet(live, obj) }},
+		{factory.Core().V1().Nodes().Informer(), func(obj interface{}) { upsertNode(live, obj) }},
+	}
+
+	for _, h := range handlers {
+		h := h // capture per-iteration copy: UpdateFunc closes over this below
+		_, err := h.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    h.upsert,
+			UpdateFunc: func(_, obj interface{}) { h.upsert(obj) },
+			DeleteFunc: func(obj interface{}) { deleteWatched(live, obj) },
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsertPod re-runs the pod problem detectors and updates live.
+func upsertPod(live *liveReport, obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	resources, _ := podProblems(context.Background(), pod)
+	live.set("pod/"+pod.Namespace+"/"+pod.Name, resources)
+}
+
+// upsertDeployment re-runs the deployment problem detector and updates
+// live.
+func upsertDeployment(live *liveReport, obj interface{}) {
+	d, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+
+	res, is := deploymentProblem(d)
+	resources := []Resource(nil)
+	if is {
+		resources = []Resource{res}
+	}
+	live.set("deployment/"+d.Namespace+"/"+d.Name, resources)
+}
+
+// upsertStatefulSet re-runs the statefulset problem detector and
+// updates live.
+func upsertStatefulSet(live *liveReport, obj interface{}) {
+	s, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return
+	}
+
+	res, is := statefulSetProblem(s)
+	resources := []Resource(nil)
+	if is {
+		resources = []Resource{res}
+	}
+	live.set("statefulset/"+s.Namespace+"/"+s.Name, resources)
+}
+
+// upsertNode re-runs the node problem detector and updates live.
+func upsertNode(live *liveReport, obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	live.set("node/"+node.Name, nodeProblems(node))
+}
+
+// deleteWatched clears whatever live entry corresponds to a deleted
+// object.
+func deleteWatched(live *liveReport, obj interface{}) {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		live.set("pod/"+o.Namespace+"/"+o.Name, nil)
+	case *appsv1.Deployment:
+		live.set("deployment/"+o.Namespace+"/"+o.Name, nil)
+	case *appsv1.StatefulSet:
+		live.set("statefulset/"+o.Namespace+"/"+o.Name, nil)
+	case *corev1.Node:
+		live.set("node/"+o.Name, nil)
+	}
+}
+
+// renderWatch periodically clears the terminal and reprints the live
+// report via the existing text formatter, until ctx is cancelled.
+func renderWatch(ctx context.Context, live *liveReport) error {
+	ticker := time.NewTicker(ttyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		resources := live.snapshot()
+		fmt.Print("\033[H\033[2J")
+		bold.Println("Watching for problems (ctrl-c to stop) ...")
+		renderText(ReportFromResources(resources), resources)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// problemsGauge is the Prometheus gauge exposed at /metrics by `checkup
+// --watch --serve`.
+var problemsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "k8r_checkup_problems",
+	Help: "Number of currently-occurring checkup problems, by problem ID, severity and namespace.",
+}, []string{"id", "severity", "namespace"})
+
+// serveWatch exposes the live report over HTTP at /report.json and the
+// Prometheus gauges at /metrics, until ctx is cancelled.
+func serveWatch(ctx context.Context, live *liveReport, addr string) error {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(problemsGauge)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/report.json", func(w http.ResponseWriter, r *http.Request) {
+		resources := live.snapshot()
+		report := ReportFromResources(resources)
+		updateProblemsGauge(resources)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := writeReport(w, OutputFormatJSON, report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	metricsHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		updateProblemsGauge(live.snapshot())
+		metricsHandler.ServeHTTP(w, r)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx) //nolint:errcheck // Why: best-effort on shutdown
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "failed to serve checkup watch")
+	}
+
+	return nil
+}
+
+// updateProblemsGauge refreshes problemsGauge from the current set of
+// problem resources so that /metrics reflects the same resources
+// /report.json does.
+func updateProblemsGauge(resources []Resource) {
+	problemsGauge.Reset()
+
+	counts := make(map[[3]string]int)
+	for _, r := range resources {
+		severity := "critical"
+		if r.Warning {
+			severity = "warning"
+		}
+
+		namespace := ""
+		if ns, _, ok := splitNamespacedName(r.Name); ok {
+			namespace = ns
+		}
+
+		counts[[3]string{r.ProblemID, severity, namespace}]++
+	}
+
+	for k, count := range counts {
+		problemsGauge.WithLabelValues(k[0], k[1], k[2]).Set(float64(count))
+	}
+}
+
+// splitNamespacedName splits a "namespace/name" resource name. Cluster-
+// scoped resources (e.g. nodes) don't have a namespace, so ok is false.
+func splitNamespacedName(name string) (namespace, resourceName string, ok bool) {
+	for i := 0; i < len(name); i++ {
+		if name[i] == '/' {
+			return name[:i], name[i+1:], true
+		}
+	}
+	return "", name, false
+}